@@ -0,0 +1,225 @@
+package tpu
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/quic-go/quic-go"
+)
+
+var DEFAULT_CONNECTION_POOL_SIZE = 1024
+var DEFAULT_QUIC_HANDSHAKE_TIMEOUT = 5 * time.Second
+var DEFAULT_QUIC_KEEP_ALIVE = 10 * time.Second
+
+// ConnectionCacheConfig tunes the bounded pool of long-lived QUIC
+// connections kept by a ConnectionCache.
+type ConnectionCacheConfig struct {
+	PoolSize         int
+	HandshakeTimeout time.Duration
+	KeepAlive        time.Duration
+}
+
+type cachedConnection struct {
+	addr       string
+	connection quic.Connection
+}
+
+// ConnectionCache is an LRU-evicted pool of long-lived QUIC connections to
+// TPU leaders, keyed by socket address. It lets a QUICTransport reuse a
+// validator's connection across sends instead of re-handshaking every time.
+type ConnectionCache struct {
+	mu        sync.Mutex
+	config    ConnectionCacheConfig
+	tlsConfig *tls.Config
+	conns     map[string]*cachedConnection
+	lru       []string // oldest first, most recently used last
+}
+
+func NewConnectionCache(config ConnectionCacheConfig) *ConnectionCache {
+	if config.PoolSize <= 0 {
+		config.PoolSize = DEFAULT_CONNECTION_POOL_SIZE
+	}
+	if config.HandshakeTimeout <= 0 {
+		config.HandshakeTimeout = DEFAULT_QUIC_HANDSHAKE_TIMEOUT
+	}
+	if config.KeepAlive <= 0 {
+		config.KeepAlive = DEFAULT_QUIC_KEEP_ALIVE
+	}
+	return &ConnectionCache{
+		config: config,
+		tlsConfig: &tls.Config{
+			InsecureSkipVerify: true,
+			NextProtos:         []string{"solana-tpu"},
+		},
+		conns: make(map[string]*cachedConnection),
+	}
+}
+
+// Get returns a pooled connection for addr, dialing and caching a new one
+// if none exists yet or the cached connection has gone away.
+func (connectionCache *ConnectionCache) Get(addr *net.UDPAddr) (quic.Connection, error) {
+	key := addr.String()
+
+	connectionCache.mu.Lock()
+	if cached, ok := connectionCache.conns[key]; ok {
+		select {
+		case <-cached.connection.Context().Done():
+			delete(connectionCache.conns, key)
+			connectionCache.removeFromLRU(key)
+		default:
+			connectionCache.touch(key)
+			connectionCache.mu.Unlock()
+			return cached.connection, nil
+		}
+	}
+	connectionCache.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), connectionCache.config.HandshakeTimeout)
+	defer cancel()
+	connection, err := quic.DialAddr(ctx, key, connectionCache.tlsConfig, &quic.Config{
+		KeepAlivePeriod: connectionCache.config.KeepAlive,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	connectionCache.mu.Lock()
+	defer connectionCache.mu.Unlock()
+	if cached, ok := connectionCache.conns[key]; ok {
+		select {
+		case <-cached.connection.Context().Done():
+			delete(connectionCache.conns, key)
+			connectionCache.removeFromLRU(key)
+		default:
+			// Another goroutine raced us and already cached a live
+			// connection for key; keep theirs and close the one we just
+			// dialed instead of leaking it out of the bounded pool.
+			connectionCache.touch(key)
+			connection.CloseWithError(0, "connection cache: lost race to cache this connection")
+			return cached.connection, nil
+		}
+	}
+	connectionCache.evictIfFull()
+	connectionCache.conns[key] = &cachedConnection{addr: key, connection: connection}
+	connectionCache.touch(key)
+	return connection, nil
+}
+
+// touch moves key to the most-recently-used end of the LRU list. Caller
+// must hold connectionCache.mu.
+func (connectionCache *ConnectionCache) touch(key string) {
+	connectionCache.removeFromLRU(key)
+	connectionCache.lru = append(connectionCache.lru, key)
+}
+
+func (connectionCache *ConnectionCache) removeFromLRU(key string) {
+	for i, existing := range connectionCache.lru {
+		if existing == key {
+			connectionCache.lru = append(connectionCache.lru[:i], connectionCache.lru[i+1:]...)
+			return
+		}
+	}
+}
+
+// evictIfFull closes and drops the least-recently-used connection once the
+// pool is at capacity. Caller must hold connectionCache.mu.
+func (connectionCache *ConnectionCache) evictIfFull() {
+	for len(connectionCache.conns) >= connectionCache.config.PoolSize && len(connectionCache.lru) > 0 {
+		oldest := connectionCache.lru[0]
+		connectionCache.lru = connectionCache.lru[1:]
+		if cached, ok := connectionCache.conns[oldest]; ok {
+			cached.connection.CloseWithError(0, "connection cache: evicted")
+			delete(connectionCache.conns, oldest)
+		}
+	}
+}
+
+func (connectionCache *ConnectionCache) Close() error {
+	connectionCache.mu.Lock()
+	defer connectionCache.mu.Unlock()
+	for key, cached := range connectionCache.conns {
+		cached.connection.CloseWithError(0, "connection cache: closed")
+		delete(connectionCache.conns, key)
+	}
+	connectionCache.lru = nil
+	return nil
+}
+
+// QUICTransport sends transactions over pooled QUIC connections, opening a
+// single unidirectional stream per send as TPU ingress expects.
+type QUICTransport struct {
+	Cache *ConnectionCache
+}
+
+func NewQUICTransport(config ConnectionCacheConfig) *QUICTransport {
+	return &QUICTransport{Cache: NewConnectionCache(config)}
+}
+
+func (quicTransport *QUICTransport) Send(endpoint *LeaderEndpoint, transaction []byte) error {
+	if endpoint == nil || endpoint.Addr == nil {
+		return errors.New("tpu: nil QUIC endpoint")
+	}
+	if endpoint.Protocol != ProtocolQUIC {
+		return fmt.Errorf("tpu: QUICTransport cannot send to a %s endpoint", endpoint.Protocol)
+	}
+	connection, err := quicTransport.Cache.Get(endpoint.Addr)
+	if err != nil {
+		return err
+	}
+	stream, err := connection.OpenUniStreamSync(context.Background())
+	if err != nil {
+		return err
+	}
+	defer stream.Close()
+	_, err = stream.Write(transaction)
+	return err
+}
+
+func (quicTransport *QUICTransport) Close() error {
+	return quicTransport.Cache.Close()
+}
+
+// AutoTransport dispatches each send to the underlying UDP or QUIC Transport
+// based on the endpoint's tagged Protocol, so a single TPUClient can fan out
+// to a leader set that mixes QUIC-advertised and UDP-only validators.
+type AutoTransport struct {
+	UDP  Transport
+	QUIC Transport
+}
+
+// NewAutoTransport builds an AutoTransport backed by a fresh UDPTransport and
+// a QUICTransport using config.
+func NewAutoTransport(config ConnectionCacheConfig) *AutoTransport {
+	return &AutoTransport{
+		UDP:  NewUDPTransport(),
+		QUIC: NewQUICTransport(config),
+	}
+}
+
+func (autoTransport *AutoTransport) Send(endpoint *LeaderEndpoint, transaction []byte) error {
+	if endpoint == nil {
+		return errors.New("tpu: nil endpoint")
+	}
+	switch endpoint.Protocol {
+	case ProtocolQUIC:
+		return autoTransport.QUIC.Send(endpoint, transaction)
+	case ProtocolUDP:
+		return autoTransport.UDP.Send(endpoint, transaction)
+	default:
+		return fmt.Errorf("tpu: AutoTransport has no sender for %s endpoints", endpoint.Protocol)
+	}
+}
+
+func (autoTransport *AutoTransport) Close() error {
+	udpErr := autoTransport.UDP.Close()
+	quicErr := autoTransport.QUIC.Close()
+	if udpErr != nil {
+		return udpErr
+	}
+	return quicErr
+}
@@ -0,0 +1,95 @@
+package tpu
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// ConnectionState describes the health of the slot-updates websocket that
+// backs a LeaderTPUService's slot estimation.
+type ConnectionState int32
+
+const (
+	Connected ConnectionState = iota
+	Reconnecting
+	Failed
+)
+
+func (connectionState ConnectionState) String() string {
+	switch connectionState {
+	case Connected:
+		return "connected"
+	case Reconnecting:
+		return "reconnecting"
+	case Failed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// HealthMetrics is a point-in-time snapshot of a LeaderTPUService's
+// websocket health and send counters, suitable for Prometheus-style
+// scraping.
+type HealthMetrics struct {
+	State           ConnectionState
+	SuccessfulSends uint64
+	Reconnects      uint64
+	LastError       string
+}
+
+// health tracks connection state and counters behind atomics/a mutex so it
+// can be read via Health() from any goroutine while receiveSlotsUpdates and
+// SendRawTransaction update it concurrently.
+type health struct {
+	state           int32
+	successfulSends uint64
+	reconnects      uint64
+
+	mu        sync.RWMutex
+	lastError string
+}
+
+func (h *health) setState(state ConnectionState) {
+	atomic.StoreInt32(&h.state, int32(state))
+}
+
+func (h *health) recordSend() {
+	atomic.AddUint64(&h.successfulSends, 1)
+}
+
+func (h *health) recordReconnect() {
+	atomic.AddUint64(&h.reconnects, 1)
+}
+
+func (h *health) recordError(err error) {
+	if err == nil {
+		return
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.lastError = err.Error()
+}
+
+func (h *health) snapshot() HealthMetrics {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return HealthMetrics{
+		State:           ConnectionState(atomic.LoadInt32(&h.state)),
+		SuccessfulSends: atomic.LoadUint64(&h.successfulSends),
+		Reconnects:      atomic.LoadUint64(&h.reconnects),
+		LastError:       h.lastError,
+	}
+}
+
+// Health reports the current state of the slot-updates websocket plus
+// running counters, so a caller can fall back to RpcClient.SendTransaction
+// once the connection is Failed for too long.
+func (leaderTPUService *LeaderTPUService) Health() HealthMetrics {
+	return leaderTPUService.health.snapshot()
+}
+
+// Health reports the underlying LeaderTPUService's websocket health.
+func (tpuClient *TPUClient) Health() HealthMetrics {
+	return tpuClient.LTPUService.Health()
+}
@@ -0,0 +1,38 @@
+package tpu
+
+import (
+	"sort"
+
+	"github.com/RoboticAgile/solana-go"
+)
+
+// LeaderScheduleStrategy orders the unique upcoming leaders a fanout call
+// found, so that when TPUClientConfig.MaxLeaders is smaller than the number
+// of unique leaders, the ones kept after truncation are the ones most worth
+// reaching.
+type LeaderScheduleStrategy interface {
+	Order(uniqueLeaders []solana.PublicKey, stakeWeights map[string]uint64) []solana.PublicKey
+}
+
+// FirstSeenStrategy keeps leaders in the order they first appear in the
+// upcoming slot schedule. This is LeaderTPUCache's default and matches the
+// client's original, unweighted behaviour.
+type FirstSeenStrategy struct{}
+
+func (firstSeenStrategy FirstSeenStrategy) Order(uniqueLeaders []solana.PublicKey, stakeWeights map[string]uint64) []solana.PublicKey {
+	return uniqueLeaders
+}
+
+// StakeWeightedStrategy orders upcoming leaders by activated stake,
+// descending, so the highest-stake near-term leaders are dialed first.
+// Leaders missing from stakeWeights (never seen in GetVoteAccounts) sort
+// last, ties broken by keeping the original slot order.
+type StakeWeightedStrategy struct{}
+
+func (stakeWeightedStrategy StakeWeightedStrategy) Order(uniqueLeaders []solana.PublicKey, stakeWeights map[string]uint64) []solana.PublicKey {
+	ordered := append([]solana.PublicKey(nil), uniqueLeaders...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return stakeWeights[ordered[i].String()] > stakeWeights[ordered[j].String()]
+	})
+	return ordered
+}
@@ -0,0 +1,177 @@
+package tpu
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RoboticAgile/solana-go"
+	"github.com/RoboticAgile/solana-go/rpc"
+)
+
+// TRANSACTION_RESEND_INTERVAL is how often unconfirmed signatures are
+// rebroadcast to the current leader fanout while SendAndConfirmTransaction
+// waits for confirmation.
+var TRANSACTION_RESEND_INTERVAL = 2 * time.Second
+
+// MAX_SIGNATURE_STATUS_BATCH is the largest number of signatures a single
+// GetSignatureStatuses call will accept.
+var MAX_SIGNATURE_STATUS_BATCH = 256
+
+// SendAndConfirmOptions configures SendAndConfirmTransaction and
+// SendMessagesWithSigners.
+type SendAndConfirmOptions struct {
+	// Commitment a signature must reach before it is considered confirmed.
+	// Defaults to rpc.CommitmentConfirmed.
+	Commitment rpc.Commitment
+	// ResendInterval overrides TRANSACTION_RESEND_INTERVAL for this call.
+	ResendInterval time.Duration
+	// ResendAmount is how many times a raw transaction is written to each
+	// leader socket per resend tick. Defaults to 1.
+	ResendAmount int
+	// OnProgress, if set, is invoked after every resend tick with the
+	// number of signatures still unconfirmed out of the total submitted,
+	// so callers can render progress to the user.
+	OnProgress func(unconfirmed int, total int)
+}
+
+// SendResult is the outcome of sending and confirming a single transaction.
+type SendResult struct {
+	Signature solana.Signature
+	Err       error
+}
+
+// SendAndConfirmTransaction submits transaction to the current leader
+// fanout and rebroadcasts it to the updated fanout every resend interval
+// until its signature reaches opts.Commitment or ctx is done.
+func (tpuClient *TPUClient) SendAndConfirmTransaction(ctx context.Context, transaction *solana.Transaction, opts SendAndConfirmOptions) (solana.Signature, error) {
+	results := tpuClient.sendAndConfirm(ctx, []*solana.Transaction{transaction}, opts)
+	return results[0].Signature, results[0].Err
+}
+
+// SendMessagesWithSigners builds and signs one transaction per message,
+// then sends and confirms all of them concurrently, returning one
+// SendResult per message in the same order.
+func (tpuClient *TPUClient) SendMessagesWithSigners(ctx context.Context, messages []solana.Message, signers [][]solana.PrivateKey, opts SendAndConfirmOptions) ([]SendResult, error) {
+	if len(messages) != len(signers) {
+		return nil, errors.New("tpu: messages and signers must be the same length")
+	}
+	transactions := make([]*solana.Transaction, len(messages))
+	for i, message := range messages {
+		transaction := &solana.Transaction{Message: message}
+		if err := transaction.Sign(signers[i]); err != nil {
+			return nil, fmt.Errorf("tpu: failed to sign message %d: %w", i, err)
+		}
+		transactions[i] = transaction
+	}
+	return tpuClient.sendAndConfirm(ctx, transactions, opts), nil
+}
+
+// sendAndConfirm fans transactions out to the current leader set, then
+// polls GetSignatureStatuses and rebroadcasts whatever is still pending on
+// every resend tick until every signature is resolved or ctx is done.
+func (tpuClient *TPUClient) sendAndConfirm(ctx context.Context, transactions []*solana.Transaction, opts SendAndConfirmOptions) []SendResult {
+	commitment := opts.Commitment
+	if commitment == "" {
+		commitment = rpc.CommitmentConfirmed
+	}
+	resendInterval := opts.ResendInterval
+	if resendInterval <= 0 {
+		resendInterval = TRANSACTION_RESEND_INTERVAL
+	}
+	resendAmount := opts.ResendAmount
+	if resendAmount <= 0 {
+		resendAmount = 1
+	}
+
+	results := make([]SendResult, len(transactions))
+	pending := make(map[int][]byte, len(transactions))
+	for i, transaction := range transactions {
+		if len(transaction.Signatures) == 0 {
+			results[i].Err = errors.New("tpu: transaction is not signed")
+			continue
+		}
+		results[i].Signature = transaction.Signatures[0]
+		raw, err := transaction.MarshalBinary()
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		pending[i] = raw
+	}
+
+	broadcast := func() {
+		for i, raw := range pending {
+			if err := tpuClient.SendRawTransaction(raw, resendAmount); err != nil {
+				results[i].Err = err
+			}
+		}
+	}
+	broadcast()
+
+	ticker := time.NewTicker(resendInterval)
+	defer ticker.Stop()
+	for len(pending) > 0 {
+		select {
+		case <-ctx.Done():
+			for i := range pending {
+				results[i].Err = ctx.Err()
+			}
+			return results
+		case <-ticker.C:
+			tpuClient.resolvePending(pending, results, commitment)
+			if opts.OnProgress != nil {
+				opts.OnProgress(len(pending), len(transactions))
+			}
+			if len(pending) > 0 {
+				broadcast()
+			}
+		}
+	}
+	return results
+}
+
+// resolvePending looks up the confirmation status of every signature still
+// in pending, in batches of at most MAX_SIGNATURE_STATUS_BATCH, and removes
+// any signature that has failed or reached the requested commitment.
+func (tpuClient *TPUClient) resolvePending(pending map[int][]byte, results []SendResult, commitment rpc.Commitment) {
+	indexes := make([]int, 0, len(pending))
+	signatures := make([]solana.Signature, 0, len(pending))
+	for i := range pending {
+		indexes = append(indexes, i)
+		signatures = append(signatures, results[i].Signature)
+	}
+
+	for start := 0; start < len(signatures); start += MAX_SIGNATURE_STATUS_BATCH {
+		end := start + MAX_SIGNATURE_STATUS_BATCH
+		if end > len(signatures) {
+			end = len(signatures)
+		}
+		statuses, err := tpuClient.Connection.GetSignatureStatuses(false, signatures[start:end])
+		if err != nil {
+			continue
+		}
+		for offset, status := range statuses {
+			i := indexes[start+offset]
+			if status == nil {
+				continue
+			}
+			if status.Err != nil {
+				results[i].Err = fmt.Errorf("tpu: transaction failed: %v", status.Err)
+				delete(pending, i)
+				continue
+			}
+			if commitmentRank[status.ConfirmationStatus] >= commitmentRank[commitment] {
+				results[i].Err = nil
+				delete(pending, i)
+			}
+		}
+	}
+}
+
+var commitmentRank = map[rpc.Commitment]int{
+	rpc.CommitmentProcessed: 0,
+	rpc.CommitmentConfirmed: 1,
+	rpc.CommitmentFinalized: 2,
+}
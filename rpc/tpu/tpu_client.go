@@ -7,8 +7,10 @@ import (
 	"github.com/RoboticAgile/solana-go/rpc"
 	"github.com/RoboticAgile/solana-go/rpc/ws"
 	"math"
+	"math/rand"
 	"net"
 	"sort"
+	"sync"
 	"time"
 )
 
@@ -16,13 +18,40 @@ var MAX_SLOT_SKIP_DISTANCE uint64 = 48
 var DEFAULT_FANOUT_SLOTS uint64 = 12
 var MAX_FANOUT_SLOTS uint64 = 100
 
+// MIN_RECONNECT_BACKOFF and MAX_RECONNECT_BACKOFF bound the exponential
+// backoff the slot-updates websocket supervisor uses between reconnect
+// attempts.
+var MIN_RECONNECT_BACKOFF = 500 * time.Millisecond
+var MAX_RECONNECT_BACKOFF = 30 * time.Second
+
+// LeaderTPUSockets holds every TPU ingress socket a cluster node advertised,
+// keyed by protocol so callers can pick the fastest path the leader supports.
+type LeaderTPUSockets struct {
+	TPU        string
+	TPUForward string
+	TPUQUIC    string
+}
+
 type LeaderTPUCache struct {
-	LeaderTPUMap      map[string]string
+	LeaderTPUMap      map[string]LeaderTPUSockets
 	Connection        *rpc.Client
 	FirstSlot         uint64
 	SlotsInEpoch      uint64
 	LastEpochInfoSlot uint64
 	Leaders           []solana.PublicKey
+
+	// Strategy orders the unique upcoming leaders GetLeaderSockets and
+	// GetLeaderSocketsConverted return. Defaults to FirstSeenStrategy when
+	// nil.
+	Strategy LeaderScheduleStrategy
+	// StakeWeights is the per-leader activated stake used by
+	// StakeWeightedStrategy, refreshed by FetchStakeWeights.
+	StakeWeights map[string]uint64
+
+	// mu guards LeaderTPUMap, Leaders, and StakeWeights, which Run() mutates
+	// in the background while GetLeaderSockets and friends may be read
+	// concurrently from a caller's goroutine.
+	mu sync.RWMutex
 }
 
 func (leaderTPUCache *LeaderTPUCache) Load(connection *rpc.Client, startSlot uint64) error {
@@ -54,25 +83,80 @@ func (leaderTPUCache *LeaderTPUCache) FetchSlotLeaders(startSlot uint64, slotsIn
 	return slotLeaders, nil
 }
 
-func (leaderTPUCache *LeaderTPUCache) FetchClusterTPUSockets() (map[string]string, error) {
-	var clusterTPUSockets = make(map[string]string)
+func (leaderTPUCache *LeaderTPUCache) FetchClusterTPUSockets() (map[string]LeaderTPUSockets, error) {
+	var clusterTPUSockets = make(map[string]LeaderTPUSockets)
 	clusterNodes, err := leaderTPUCache.Connection.GetClusterNodes()
 	if err != nil {
 		return nil, err
 	}
 	for _, contactInfo := range clusterNodes {
+		var sockets LeaderTPUSockets
 		if contactInfo.TPU != nil {
-			clusterTPUSockets[contactInfo.Pubkey.String()] = *contactInfo.TPU
+			sockets.TPU = *contactInfo.TPU
+		}
+		if contactInfo.TPUForwards != nil {
+			sockets.TPUForward = *contactInfo.TPUForwards
+		}
+		if contactInfo.TPUQUIC != nil {
+			sockets.TPUQUIC = *contactInfo.TPUQUIC
+		}
+		if sockets.TPU != "" || sockets.TPUForward != "" || sockets.TPUQUIC != "" {
+			clusterTPUSockets[contactInfo.Pubkey.String()] = sockets
 		}
 	}
 	return clusterTPUSockets, nil
 }
 
+// FetchStakeWeights queries GetVoteAccounts once and sums each validator
+// identity's activated stake across its vote accounts, for use by
+// StakeWeightedStrategy.
+func (leaderTPUCache *LeaderTPUCache) FetchStakeWeights() (map[string]uint64, error) {
+	voteAccounts, err := leaderTPUCache.Connection.GetVoteAccounts()
+	if err != nil {
+		return nil, err
+	}
+	stakeWeights := make(map[string]uint64)
+	for _, voteAccount := range voteAccounts.Current {
+		stakeWeights[voteAccount.NodePubkey.String()] += voteAccount.ActivatedStake
+	}
+	for _, voteAccount := range voteAccounts.Delinquent {
+		stakeWeights[voteAccount.NodePubkey.String()] += voteAccount.ActivatedStake
+	}
+	return stakeWeights, nil
+}
+
+// shouldRefreshStakeWeights reports whether the epoch has moved on since
+// StakeWeights was last populated, so Run only calls GetVoteAccounts once
+// per epoch even though it checks on FetchClusterTPUSockets's 5-minute
+// cadence.
+func (leaderTPUCache *LeaderTPUCache) shouldRefreshStakeWeights() bool {
+	leaderTPUCache.mu.RLock()
+	defer leaderTPUCache.mu.RUnlock()
+	if leaderTPUCache.StakeWeights == nil || leaderTPUCache.SlotsInEpoch == 0 {
+		return true
+	}
+	return leaderTPUCache.FirstSlot/leaderTPUCache.SlotsInEpoch != leaderTPUCache.LastEpochInfoSlot/leaderTPUCache.SlotsInEpoch
+}
+
+// setStakeWeights replaces the cached stake map under lock and records the
+// slot it was fetched at, so shouldRefreshStakeWeights can tell when the
+// epoch has moved on.
+func (leaderTPUCache *LeaderTPUCache) setStakeWeights(stakeWeights map[string]uint64) {
+	leaderTPUCache.mu.Lock()
+	defer leaderTPUCache.mu.Unlock()
+	leaderTPUCache.StakeWeights = stakeWeights
+	leaderTPUCache.LastEpochInfoSlot = leaderTPUCache.FirstSlot
+}
+
 func (leaderTPUCache *LeaderTPUCache) LastSlot() uint64 {
+	leaderTPUCache.mu.RLock()
+	defer leaderTPUCache.mu.RUnlock()
 	return leaderTPUCache.FirstSlot + uint64(len(leaderTPUCache.Leaders)) - 1
 }
 
 func (leaderTPUCache *LeaderTPUCache) GetSlotLeader(slot uint64) solana.PublicKey {
+	leaderTPUCache.mu.RLock()
+	defer leaderTPUCache.mu.RUnlock()
 	if slot >= leaderTPUCache.FirstSlot {
 		return leaderTPUCache.Leaders[slot-leaderTPUCache.FirstSlot]
 	} else {
@@ -81,57 +165,168 @@ func (leaderTPUCache *LeaderTPUCache) GetSlotLeader(slot uint64) solana.PublicKe
 }
 
 func (leaderTPUCache *LeaderTPUCache) GetLeaderSockets(fanoutSlots uint64) []string {
-	var alreadyCheckedLeaders []string
+	leaderTPUCache.mu.RLock()
+	defer leaderTPUCache.mu.RUnlock()
 	var leaderTPUSockets []string
-	var checkedSlots uint64 = 0
-	for _, leader := range leaderTPUCache.Leaders {
-		tpuSocket := leaderTPUCache.LeaderTPUMap[leader.String()]
-		if tpuSocket != "" {
-			isDuplicate := CheckIfDuplicate(alreadyCheckedLeaders, leader.String())
-			if !isDuplicate {
-				alreadyCheckedLeaders = append(alreadyCheckedLeaders, leader.String())
-				leaderTPUSockets = append(leaderTPUSockets, tpuSocket)
+	for _, leader := range leaderTPUCache.orderedUniqueLeaders(fanoutSlots) {
+		if tpuSocket := leaderTPUCache.LeaderTPUMap[leader.String()].TPU; tpuSocket != "" {
+			leaderTPUSockets = append(leaderTPUSockets, tpuSocket)
+		}
+	}
+	return leaderTPUSockets
+}
+
+// GetLeaderUDPSockets returns, for the next fanoutSlots slots, each unique
+// upcoming leader's advertised plain UDP TPU socket address, ordered by the
+// cache's LeaderScheduleStrategy. Unlike GetLeaderSocketsConverted, this
+// never substitutes the leader's advertised QUIC socket, so it stays usable
+// for sending over a caller-supplied raw UDP socket.
+func (leaderTPUCache *LeaderTPUCache) GetLeaderUDPSockets(fanoutSlots uint64) []*net.UDPAddr {
+	leaderTPUCache.mu.RLock()
+	defer leaderTPUCache.mu.RUnlock()
+	var udpAddrs []*net.UDPAddr
+	for _, leader := range leaderTPUCache.orderedUniqueLeaders(fanoutSlots) {
+		tpuSocket := leaderTPUCache.LeaderTPUMap[leader.String()].TPU
+		if tpuSocket == "" {
+			continue
+		}
+		addr, err := net.ResolveUDPAddr("udp", tpuSocket)
+		if err != nil {
+			continue
+		}
+		udpAddrs = append(udpAddrs, addr)
+	}
+	return udpAddrs
+}
+
+// GetLeaderQUICSockets returns, for the next fanoutSlots slots, each unique
+// upcoming leader's QUIC TPU socket address, ordered by the cache's
+// LeaderScheduleStrategy: the advertised TPUQUIC socket when present,
+// otherwise the QUIC port derived from the advertised UDP socket via
+// QUIC_PORT_OFFSET. Unlike GetLeaderSocketsConverted, every address returned
+// is QUIC, so a caller that has decided to force QUIC everywhere doesn't
+// fall back to UDPTransport for leaders that didn't explicitly advertise a
+// QUIC socket.
+func (leaderTPUCache *LeaderTPUCache) GetLeaderQUICSockets(fanoutSlots uint64) []*net.UDPAddr {
+	leaderTPUCache.mu.RLock()
+	defer leaderTPUCache.mu.RUnlock()
+	var quicAddrs []*net.UDPAddr
+	for _, leader := range leaderTPUCache.orderedUniqueLeaders(fanoutSlots) {
+		sockets := leaderTPUCache.LeaderTPUMap[leader.String()]
+		if sockets.TPUQUIC != "" {
+			if addr, err := net.ResolveUDPAddr("udp", sockets.TPUQUIC); err == nil {
+				quicAddrs = append(quicAddrs, addr)
 			}
+			continue
 		}
-		checkedSlots++
-		if checkedSlots == fanoutSlots {
-			return leaderTPUSockets
+		if sockets.TPU != "" {
+			if addr, err := net.ResolveUDPAddr("udp", sockets.TPU); err == nil {
+				quicAddrs = append(quicAddrs, DeriveQUICPort(addr))
+			}
 		}
 	}
-	return leaderTPUSockets
+	return quicAddrs
+}
+
+// GetLeaderSocketsConverted returns, for the next fanoutSlots slots, the
+// resolved TPU endpoint to send to for each unique upcoming leader, tagged
+// with the protocol a caller's Transport should use to reach it: QUIC when
+// the leader advertised a TPUQUIC socket, otherwise its plain advertised UDP
+// socket. Leaders are ordered by the cache's LeaderScheduleStrategy, so a
+// caller truncating to TPUClientConfig.MaxLeaders entries still reaches the
+// highest-priority leaders.
+func (leaderTPUCache *LeaderTPUCache) GetLeaderSocketsConverted(fanoutSlots uint64) []*LeaderEndpoint {
+	leaderTPUCache.mu.RLock()
+	defer leaderTPUCache.mu.RUnlock()
+	var leaderEndpoints []*LeaderEndpoint
+	for _, leader := range leaderTPUCache.orderedUniqueLeaders(fanoutSlots) {
+		if endpoint := leaderEndpointFor(leaderTPUCache.LeaderTPUMap[leader.String()]); endpoint != nil {
+			leaderEndpoints = append(leaderEndpoints, endpoint)
+		}
+	}
+	return leaderEndpoints
 }
 
-func (leaderTPUCache *LeaderTPUCache) GetLeaderSocketsConverted(fanoutSlots uint64) []*net.UDPAddr {
+// orderedUniqueLeaders returns the unique leaders among the next
+// fanoutSlots slots that have an advertised TPU socket, deduped by first
+// occurrence and then ordered by leaderTPUCache.Strategy (first-seen slot
+// order by default). Callers must already hold at least a read lock on mu.
+func (leaderTPUCache *LeaderTPUCache) orderedUniqueLeaders(fanoutSlots uint64) []solana.PublicKey {
 	var alreadyCheckedLeaders []string
-	var leaderTPUSockets []*net.UDPAddr
+	var uniqueLeaders []solana.PublicKey
 	var checkedSlots uint64 = 0
 	for _, leader := range leaderTPUCache.Leaders {
-		tpuSocket := leaderTPUCache.LeaderTPUMap[leader.String()]
-		if tpuSocket != "" {
-			isDuplicate := CheckIfDuplicate(alreadyCheckedLeaders, leader.String())
-			if !isDuplicate {
+		sockets := leaderTPUCache.LeaderTPUMap[leader.String()]
+		if sockets.TPU != "" || sockets.TPUQUIC != "" {
+			if !CheckIfDuplicate(alreadyCheckedLeaders, leader.String()) {
 				alreadyCheckedLeaders = append(alreadyCheckedLeaders, leader.String())
-				leaderAddress, _ := net.ResolveUDPAddr("udp", tpuSocket)
-				leaderTPUSockets = append(leaderTPUSockets, leaderAddress)
+				uniqueLeaders = append(uniqueLeaders, leader)
 			}
 		}
 		checkedSlots++
 		if checkedSlots == fanoutSlots {
-			return leaderTPUSockets
+			break
 		}
 	}
-	return leaderTPUSockets
+	strategy := leaderTPUCache.Strategy
+	if strategy == nil {
+		strategy = FirstSeenStrategy{}
+	}
+	return strategy.Order(uniqueLeaders, leaderTPUCache.StakeWeights)
+}
+
+// setLeaderTPUMap replaces the cached cluster TPU sockets under lock.
+func (leaderTPUCache *LeaderTPUCache) setLeaderTPUMap(leaderTPUMap map[string]LeaderTPUSockets) {
+	leaderTPUCache.mu.Lock()
+	defer leaderTPUCache.mu.Unlock()
+	leaderTPUCache.LeaderTPUMap = leaderTPUMap
+}
+
+// setLeaders replaces the cached slot leader schedule under lock.
+func (leaderTPUCache *LeaderTPUCache) setLeaders(firstSlot uint64, leaders []solana.PublicKey) {
+	leaderTPUCache.mu.Lock()
+	defer leaderTPUCache.mu.Unlock()
+	leaderTPUCache.FirstSlot = firstSlot
+	leaderTPUCache.Leaders = leaders
+}
+
+// leaderEndpointFor picks the best available endpoint for a leader: the
+// advertised QUIC socket if the leader advertised one, otherwise its plain
+// UDP socket. It never guesses a QUIC port for a leader that didn't
+// advertise TPUQUIC - see GetLeaderQUICSockets for that.
+func leaderEndpointFor(sockets LeaderTPUSockets) *LeaderEndpoint {
+	if sockets.TPUQUIC != "" {
+		if addr, err := net.ResolveUDPAddr("udp", sockets.TPUQUIC); err == nil {
+			return &LeaderEndpoint{Addr: addr, Protocol: ProtocolQUIC}
+		}
+	}
+	if sockets.TPU != "" {
+		addr, err := net.ResolveUDPAddr("udp", sockets.TPU)
+		if err != nil {
+			return nil
+		}
+		return &LeaderEndpoint{Addr: addr, Protocol: ProtocolUDP}
+	}
+	return nil
 }
 
 type RecentLeaderSlots struct {
 	RecentSlots []float64
+
+	// mu guards RecentSlots, which the websocket receiver goroutine appends
+	// to while EstimatedCurrentSlot may be read from Run() or a caller.
+	mu sync.RWMutex
 }
 
 func (recentLeaderSlots *RecentLeaderSlots) Load(currentSlot uint64) {
+	recentLeaderSlots.mu.Lock()
+	defer recentLeaderSlots.mu.Unlock()
 	recentLeaderSlots.RecentSlots = append(recentLeaderSlots.RecentSlots, float64(currentSlot))
 }
 
 func (recentLeaderSlots *RecentLeaderSlots) RecordSlot(currentSlot uint64) {
+	recentLeaderSlots.mu.Lock()
+	defer recentLeaderSlots.mu.Unlock()
 	recentLeaderSlots.RecentSlots = append(recentLeaderSlots.RecentSlots, float64(currentSlot))
 	for len(recentLeaderSlots.RecentSlots) > 12 {
 		recentLeaderSlots.RecentSlots = recentLeaderSlots.RecentSlots[1:]
@@ -139,10 +334,14 @@ func (recentLeaderSlots *RecentLeaderSlots) RecordSlot(currentSlot uint64) {
 }
 
 func (recentLeaderSlots *RecentLeaderSlots) EstimatedCurrentSlot() uint64 {
+	recentLeaderSlots.mu.RLock()
+	defer recentLeaderSlots.mu.RUnlock()
 	if len(recentLeaderSlots.RecentSlots) == 0 {
 		return 0
 	}
-	recentSlots := recentLeaderSlots.RecentSlots
+	// Sorted in place below, so operate on a copy: other readers may hold
+	// the read lock at the same time and must not see the slice reordered.
+	recentSlots := append([]float64(nil), recentLeaderSlots.RecentSlots...)
 	sort.Float64s(recentSlots)
 	maxIndex := len(recentSlots) - 1
 	medianIndex := maxIndex / 2
@@ -166,10 +365,48 @@ type LeaderTPUService struct {
 	Connection        *rpc.Client
 	WSConnection      *ws.Client
 	LeaderConnections []net.Conn
+
+	websocketURL string
+	health       health
+
+	// wsMu guards WSConnection and Subscription, which reconnect replaces
+	// from the receiveSlotsUpdates goroutine while Close reads and closes
+	// them from whatever goroutine calls Close.
+	wsMu sync.Mutex
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// installWSConnection installs a freshly (re)established connection and
+// subscription, unless the service has already been closed - in which case
+// it closes the new connection itself instead, so a reconnect that finishes
+// after Close never leaves a live websocket connection that nothing closes.
+func (leaderTPUService *LeaderTPUService) installWSConnection(wsConnection *ws.Client, subscription *ws.SlotsUpdatesSubscription) bool {
+	leaderTPUService.wsMu.Lock()
+	defer leaderTPUService.wsMu.Unlock()
+	if leaderTPUService.ctx.Err() != nil {
+		wsConnection.Close()
+		return false
+	}
+	leaderTPUService.WSConnection = wsConnection
+	leaderTPUService.Subscription = subscription
+	return true
+}
+
+// currentSubscription returns the active subscription under lock, so
+// receiveSlotsUpdates never reads a Subscription that reconnect is
+// concurrently replacing.
+func (leaderTPUService *LeaderTPUService) currentSubscription() *ws.SlotsUpdatesSubscription {
+	leaderTPUService.wsMu.Lock()
+	defer leaderTPUService.wsMu.Unlock()
+	return leaderTPUService.Subscription
 }
 
 func (leaderTPUService *LeaderTPUService) Load(connection *rpc.Client, websocketURL string, fanout uint64) error {
 	leaderTPUService.Connection = connection
+	leaderTPUService.ctx, leaderTPUService.cancel = context.WithCancel(context.Background())
 	slot, err := leaderTPUService.Connection.GetSlot(rpc.CommitmentProcessed)
 	if err != nil {
 		return err
@@ -183,45 +420,143 @@ func (leaderTPUService *LeaderTPUService) Load(connection *rpc.Client, websocket
 		return err
 	}
 	leaderTPUService.LTPUCache = &leaderTPUCache
+	leaderTPUService.websocketURL = websocketURL
 	if websocketURL != "" {
 		wsConnection, err := ws.Connect(context.TODO(), websocketURL)
 		if err == nil {
 			subscription, err := wsConnection.SlotsUpdatesSubscribe()
 			if err == nil {
+				leaderTPUService.WSConnection = wsConnection
 				leaderTPUService.Subscription = subscription
-				go func() {
-					for {
-						message, err := leaderTPUService.Subscription.Recv()
-						if err == nil {
-							//Slot already full, skip over 1 slot.
-							if message.Type == ws.SlotsUpdatesCompleted {
-								leaderTPUService.RecentSlots.RecordSlot(message.Slot + 1)
-							}
-							//Slot received first shred, it's still accepting transactions so we record.
-							if message.Type == ws.SlotsUpdatesFirstShredReceived {
-								leaderTPUService.RecentSlots.RecordSlot(message.Slot)
-							}
-						}
-					}
-				}()
+				leaderTPUService.health.setState(Connected)
+				leaderTPUService.wg.Add(1)
+				go leaderTPUService.receiveSlotsUpdates()
 			} else {
+				leaderTPUService.health.setState(Failed)
 				leaderTPUService.Connection = nil
 			}
 		} else {
+			leaderTPUService.health.setState(Failed)
 			leaderTPUService.Connection = nil
 		}
 	} else {
+		leaderTPUService.health.setState(Failed)
 		leaderTPUService.Connection = nil
 	}
+	leaderTPUService.wg.Add(1)
 	go leaderTPUService.Run(fanout)
 	return nil
 }
 
+// receiveSlotsUpdates drains the slot-updates subscription into RecentSlots
+// until Close cancels the service's context. Whenever Recv errors out - a
+// dropped connection, a server restart - it hands off to reconnect instead
+// of spinning or giving up, so slot estimation keeps working off the
+// websocket instead of silently degrading to RPC polling forever.
+func (leaderTPUService *LeaderTPUService) receiveSlotsUpdates() {
+	defer leaderTPUService.wg.Done()
+	backoff := MIN_RECONNECT_BACKOFF
+	for {
+		if leaderTPUService.ctx.Err() != nil {
+			return
+		}
+		message, err := leaderTPUService.currentSubscription().Recv()
+		if err != nil {
+			leaderTPUService.health.recordError(err)
+			if leaderTPUService.ctx.Err() != nil {
+				return
+			}
+			if !leaderTPUService.reconnect(&backoff) {
+				return
+			}
+			continue
+		}
+		//Slot already full, skip over 1 slot.
+		if message.Type == ws.SlotsUpdatesCompleted {
+			leaderTPUService.RecentSlots.RecordSlot(message.Slot + 1)
+		}
+		//Slot received first shred, it's still accepting transactions so we record.
+		if message.Type == ws.SlotsUpdatesFirstShredReceived {
+			leaderTPUService.RecentSlots.RecordSlot(message.Slot)
+		}
+	}
+}
+
+// reconnect re-establishes the slot-updates websocket and subscription,
+// retrying with exponential backoff (MIN_RECONNECT_BACKOFF up to
+// MAX_RECONNECT_BACKOFF) plus jitter between attempts. It returns false if
+// the service's context is cancelled while waiting, and true once a new
+// subscription is in place.
+func (leaderTPUService *LeaderTPUService) reconnect(backoff *time.Duration) bool {
+	leaderTPUService.health.setState(Reconnecting)
+	for {
+		wsConnection, err := ws.Connect(context.TODO(), leaderTPUService.websocketURL)
+		if err == nil {
+			var subscription *ws.SlotsUpdatesSubscription
+			subscription, err = wsConnection.SlotsUpdatesSubscribe()
+			if err == nil {
+				if !leaderTPUService.installWSConnection(wsConnection, subscription) {
+					return false
+				}
+				leaderTPUService.health.recordReconnect()
+				leaderTPUService.health.setState(Connected)
+				*backoff = MIN_RECONNECT_BACKOFF
+				return true
+			}
+			wsConnection.Close()
+		}
+		leaderTPUService.health.recordError(err)
+		leaderTPUService.health.setState(Failed)
+
+		select {
+		case <-leaderTPUService.ctx.Done():
+			return false
+		case <-time.After(jitteredBackoff(*backoff)):
+		}
+
+		*backoff *= 2
+		if *backoff > MAX_RECONNECT_BACKOFF {
+			*backoff = MAX_RECONNECT_BACKOFF
+		}
+	}
+}
+
+// jitteredBackoff returns a duration in [backoff/2, backoff), so repeated
+// reconnect attempts across many clients don't all land on the same tick.
+func jitteredBackoff(backoff time.Duration) time.Duration {
+	half := backoff / 2
+	if half <= 0 {
+		return backoff
+	}
+	return half + time.Duration(rand.Int63n(int64(half)))
+}
+
 func (leaderTPUService *LeaderTPUService) LeaderTPUSockets(fanoutSlots uint64) []string {
 	return leaderTPUService.LTPUCache.GetLeaderSockets(fanoutSlots)
 }
 
+// Close stops Run and the slot-updates receiver, unsubscribes and closes
+// the websocket connection, and waits for both goroutines to exit.
+func (leaderTPUService *LeaderTPUService) Close() error {
+	if leaderTPUService.cancel != nil {
+		leaderTPUService.cancel()
+	}
+	leaderTPUService.wsMu.Lock()
+	subscription := leaderTPUService.Subscription
+	wsConnection := leaderTPUService.WSConnection
+	leaderTPUService.wsMu.Unlock()
+	if subscription != nil {
+		subscription.Unsubscribe()
+	}
+	if wsConnection != nil {
+		wsConnection.Close()
+	}
+	leaderTPUService.wg.Wait()
+	return nil
+}
+
 func (leaderTPUService *LeaderTPUService) Run(fanout uint64) {
+	defer leaderTPUService.wg.Done()
 	var lastClusterRefreshTime = time.Now()
 	var sleepMs = 1000
 	for {
@@ -231,11 +566,21 @@ func (leaderTPUService *LeaderTPUService) Run(fanout uint64) {
 				sleepMs = 100
 				continue
 			}
-			leaderTPUService.LTPUCache.LeaderTPUMap = latestTPUSockets
+			leaderTPUService.LTPUCache.setLeaderTPUMap(latestTPUSockets)
+
+			if leaderTPUService.LTPUCache.shouldRefreshStakeWeights() {
+				if stakeWeights, err := leaderTPUService.LTPUCache.FetchStakeWeights(); err == nil {
+					leaderTPUService.LTPUCache.setStakeWeights(stakeWeights)
+				}
+			}
 			lastClusterRefreshTime = time.Now()
 		}
 
-		time.Sleep(time.Duration(sleepMs) * time.Millisecond)
+		select {
+		case <-leaderTPUService.ctx.Done():
+			return
+		case <-time.After(time.Duration(sleepMs) * time.Millisecond):
+		}
 
 		currentSlot := leaderTPUService.RecentSlots.EstimatedCurrentSlot()
 		if currentSlot >= (leaderTPUService.LTPUCache.LastSlot() - fanout) {
@@ -244,8 +589,7 @@ func (leaderTPUService *LeaderTPUService) Run(fanout uint64) {
 				sleepMs = 100
 				continue
 			}
-			leaderTPUService.LTPUCache.FirstSlot = currentSlot
-			leaderTPUService.LTPUCache.Leaders = slotLeaders
+			leaderTPUService.LTPUCache.setLeaders(currentSlot, slotLeaders)
 		}
 		sleepMs = 1000
 	}
@@ -253,25 +597,51 @@ func (leaderTPUService *LeaderTPUService) Run(fanout uint64) {
 
 type TPUClientConfig struct {
 	FanoutSlots uint64
+	// Transport picks how raw transactions are sent to leaders. Defaults to
+	// AutoTransport when nil, which dispatches each send by the endpoint's
+	// tagged Protocol so a fanout mixing QUIC and UDP-only leaders is
+	// handled correctly. Pass a bare UDPTransport or QUICTransport (see
+	// NewQUICTransport) to send over a single protocol instead; endpoints are
+	// still tagged as GetLeaderSocketsConverted picks them, so leaders whose
+	// endpoint carries the other protocol are not sent to.
+	Transport Transport
+	// Strategy orders the unique upcoming leaders a fanout call sends to.
+	// Defaults to FirstSeenStrategy when nil; pass StakeWeightedStrategy to
+	// prioritize high-stake leaders when MaxLeaders is small.
+	Strategy LeaderScheduleStrategy
+	// MaxLeaders caps how many distinct leaders a single send fans out to.
+	// Zero (the default) means no cap: every unique leader within
+	// FanoutSlots is sent to. Combine with StakeWeightedStrategy so a small
+	// MaxLeaders still reaches the highest-stake near-term leaders.
+	MaxLeaders int
 }
 
 type TPUClient struct {
 	FanoutSlots uint64
+	MaxLeaders  int
 	LTPUService *LeaderTPUService
-	Exit        bool
 	Connection  *rpc.Client
+	Transport   Transport
 }
 
 func (tpuClient *TPUClient) Load(connection *rpc.Client, websocketURL string, config TPUClientConfig) error {
 	tpuClient.Connection = connection
 	tpuClient.FanoutSlots = uint64(math.Max(math.Min(float64(config.FanoutSlots), float64(MAX_FANOUT_SLOTS)), 1))
-	tpuClient.Exit = false
+	tpuClient.MaxLeaders = config.MaxLeaders
+	if config.Transport != nil {
+		tpuClient.Transport = config.Transport
+	} else {
+		tpuClient.Transport = NewAutoTransport(ConnectionCacheConfig{})
+	}
 	leaderTPUService := LeaderTPUService{}
 	tpuClient.LTPUService = &leaderTPUService
 	err := tpuClient.LTPUService.Load(tpuClient.Connection, websocketURL, tpuClient.FanoutSlots)
 	if err != nil {
 		return err
 	}
+	if config.Strategy != nil {
+		tpuClient.LTPUService.LTPUCache.Strategy = config.Strategy
+	}
 	return nil
 }
 
@@ -299,41 +669,53 @@ func (tpuClient *TPUClient) SendTransactionThroughSocket(transaction *solana.Tra
 	return transaction.Signatures[0], nil
 }
 
+// truncateEndpoints caps endpoints to tpuClient.MaxLeaders when set,
+// keeping the leading entries so the highest-priority leaders picked by the
+// cache's LeaderScheduleStrategy are the ones actually sent to.
+func (tpuClient *TPUClient) truncateEndpoints(endpoints []*LeaderEndpoint) []*LeaderEndpoint {
+	if tpuClient.MaxLeaders > 0 && len(endpoints) > tpuClient.MaxLeaders {
+		return endpoints[:tpuClient.MaxLeaders]
+	}
+	return endpoints
+}
+
+// truncateUDPAddrs caps addrs to tpuClient.MaxLeaders when set, mirroring
+// truncateEndpoints for the raw-socket send path.
+func (tpuClient *TPUClient) truncateUDPAddrs(addrs []*net.UDPAddr) []*net.UDPAddr {
+	if tpuClient.MaxLeaders > 0 && len(addrs) > tpuClient.MaxLeaders {
+		return addrs[:tpuClient.MaxLeaders]
+	}
+	return addrs
+}
+
 func (tpuClient *TPUClient) SendRawTransaction(transaction []byte, amount int) error {
 	var successes = 0
 	var lastError = ""
-	leaderTPUSockets := tpuClient.LTPUService.LeaderTPUSockets(tpuClient.FanoutSlots)
-	for _, leader := range leaderTPUSockets {
-		var connectionTries = 0
+	leaderEndpoints := tpuClient.truncateEndpoints(tpuClient.LTPUService.LTPUCache.GetLeaderSocketsConverted(tpuClient.FanoutSlots))
+	for _, endpoint := range leaderEndpoints {
 		var failed = false
-		var connection net.Conn
-		for {
-			conn, err := net.Dial("udp", leader)
+		for connectionTries := 0; ; connectionTries++ {
+			err := tpuClient.Transport.Send(endpoint, transaction)
 			if err != nil {
 				lastError = err.Error()
 				if connectionTries < 3 {
-					connectionTries++
 					continue
-				} else {
-					failed = true
-					break
 				}
+				failed = true
 			}
-			connection = conn
 			break
 		}
-		if failed == true {
+		if failed {
 			continue
 		}
-		for i := 0; i < amount; i++ {
-			_, err := connection.Write(transaction)
-			if err != nil {
+		for i := 1; i < amount; i++ {
+			if err := tpuClient.Transport.Send(endpoint, transaction); err != nil {
 				lastError = err.Error()
-			} else {
-				successes++
+				continue
 			}
 		}
-		connection.Close()
+		successes++
+		tpuClient.LTPUService.health.recordSend()
 	}
 	if successes == 0 {
 		return errors.New(lastError)
@@ -342,15 +724,32 @@ func (tpuClient *TPUClient) SendRawTransaction(transaction []byte, amount int) e
 	}
 }
 
+// SendRawTransactionThroughSocket sends a raw transaction to every upcoming
+// leader's plain UDP TPU socket (capped at MaxLeaders when set) using a
+// caller-supplied socket, bypassing tpuClient.Transport entirely.
 func (tpuClient *TPUClient) SendRawTransactionThroughSocket(transaction []byte, amount int, socket *net.UDPConn) error {
-	for _, leader := range tpuClient.LTPUService.LTPUCache.GetLeaderSocketsConverted(tpuClient.FanoutSlots) {
+	for _, addr := range tpuClient.truncateUDPAddrs(tpuClient.LTPUService.LTPUCache.GetLeaderUDPSockets(tpuClient.FanoutSlots)) {
 		for i := 0; i < amount; i++ {
-			socket.WriteToUDP(transaction, leader)
+			socket.WriteToUDP(transaction, addr)
 		}
 	}
 	return nil
 }
 
+// Close stops the client's background LeaderTPUService and closes its
+// transport. Callers must not use the client after calling Close.
+func (tpuClient *TPUClient) Close() error {
+	if tpuClient.LTPUService != nil {
+		if err := tpuClient.LTPUService.Close(); err != nil {
+			return err
+		}
+	}
+	if tpuClient.Transport != nil {
+		return tpuClient.Transport.Close()
+	}
+	return nil
+}
+
 func New(connection *rpc.Client, websocketURL string, config TPUClientConfig) (*TPUClient, error) {
 	tpuClient := TPUClient{}
 	err := tpuClient.Load(connection, websocketURL, config)
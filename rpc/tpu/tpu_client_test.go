@@ -0,0 +1,101 @@
+package tpu
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/RoboticAgile/solana-go"
+)
+
+// recordingTransport is a Transport stub that counts sends instead of
+// touching the network, so SendRawTransaction can be exercised concurrently
+// with the background mutations Run and receiveSlotsUpdates perform.
+type recordingTransport struct {
+	mu    sync.Mutex
+	sends int
+}
+
+func (transport *recordingTransport) Send(endpoint *LeaderEndpoint, transaction []byte) error {
+	transport.mu.Lock()
+	defer transport.mu.Unlock()
+	transport.sends++
+	return nil
+}
+
+func (transport *recordingTransport) Close() error {
+	return nil
+}
+
+// TestConcurrentSendAndSlotUpdates sends transactions from several
+// goroutines while other goroutines concurrently replace LeaderTPUCache's
+// leader schedule and TPU map and record new slots - the same concurrent
+// access pattern SendRawTransaction, Run, and receiveSlotsUpdates produce in
+// practice. Run with -race to catch unguarded access to LeaderTPUCache or
+// RecentLeaderSlots.
+func TestConcurrentSendAndSlotUpdates(t *testing.T) {
+	leaders := []solana.PublicKey{{1}, {2}, {3}}
+	leaderTPUMap := map[string]LeaderTPUSockets{
+		leaders[0].String(): {TPU: "127.0.0.1:1000", TPUQUIC: "127.0.0.1:1006"},
+		leaders[1].String(): {TPU: "127.0.0.1:1001"},
+		leaders[2].String(): {TPU: "127.0.0.1:1002"},
+	}
+	leaderTPUCache := &LeaderTPUCache{
+		FirstSlot:    100,
+		SlotsInEpoch: 432000,
+		Leaders:      leaders,
+		LeaderTPUMap: leaderTPUMap,
+		Strategy:     StakeWeightedStrategy{},
+		StakeWeights: map[string]uint64{
+			leaders[0].String(): 300,
+			leaders[1].String(): 200,
+			leaders[2].String(): 100,
+		},
+	}
+	recentSlots := &RecentLeaderSlots{}
+	recentSlots.Load(100)
+
+	leaderTPUService := &LeaderTPUService{
+		LTPUCache:   leaderTPUCache,
+		RecentSlots: recentSlots,
+	}
+	transport := &recordingTransport{}
+	tpuClient := &TPUClient{
+		FanoutSlots: 10,
+		MaxLeaders:  2,
+		LTPUService: leaderTPUService,
+		Transport:   transport,
+	}
+
+	const goroutines = 8
+	const iterationsPerGoroutine = 50
+	var wg sync.WaitGroup
+	wg.Add(goroutines * 3)
+
+	for i := 0; i < goroutines; i++ {
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterationsPerGoroutine; j++ {
+				_ = tpuClient.SendRawTransaction([]byte("raw-transaction"), 1)
+			}
+		}()
+		go func(slot uint64) {
+			defer wg.Done()
+			for j := 0; j < iterationsPerGoroutine; j++ {
+				recentSlots.RecordSlot(slot + uint64(j))
+				recentSlots.EstimatedCurrentSlot()
+			}
+		}(uint64(100 + i*iterationsPerGoroutine))
+		go func() {
+			defer wg.Done()
+			for j := 0; j < iterationsPerGoroutine; j++ {
+				leaderTPUCache.setLeaderTPUMap(leaderTPUMap)
+				leaderTPUCache.setLeaders(100, leaders)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if transport.sends == 0 {
+		t.Fatal("expected SendRawTransaction to reach the recording transport at least once")
+	}
+}
@@ -0,0 +1,88 @@
+package tpu
+
+import (
+	"errors"
+	"fmt"
+	"net"
+)
+
+// Protocol identifies the wire protocol used to reach a leader's TPU port.
+type Protocol int
+
+const (
+	ProtocolUDP Protocol = iota
+	ProtocolQUIC
+)
+
+func (protocol Protocol) String() string {
+	switch protocol {
+	case ProtocolUDP:
+		return "UDP"
+	case ProtocolQUIC:
+		return "QUIC"
+	default:
+		return "unknown"
+	}
+}
+
+// QUIC_PORT_OFFSET is the number of ports above the advertised UDP TPU port
+// that a validator's QUIC TPU listener is conventionally bound to, when the
+// cluster node does not advertise a dedicated QUIC socket.
+var QUIC_PORT_OFFSET uint16 = 6
+
+// LeaderEndpoint pairs a resolved TPU socket address with the protocol a
+// Transport should use to reach it.
+type LeaderEndpoint struct {
+	Addr     *net.UDPAddr
+	Protocol Protocol
+}
+
+// Transport sends an already-serialized transaction to a single leader
+// endpoint. Implementations may keep long-lived state (e.g. pooled QUIC
+// connections) and must be safe for concurrent use.
+type Transport interface {
+	Send(endpoint *LeaderEndpoint, transaction []byte) error
+	Close() error
+}
+
+// UDPTransport dials a fresh UDP socket for every send, matching the TPU
+// client's original behaviour. It only handles ProtocolUDP endpoints; use
+// AutoTransport to handle a fanout that mixes UDP and QUIC endpoints.
+type UDPTransport struct{}
+
+func NewUDPTransport() *UDPTransport {
+	return &UDPTransport{}
+}
+
+func (udpTransport *UDPTransport) Send(endpoint *LeaderEndpoint, transaction []byte) error {
+	if endpoint == nil || endpoint.Addr == nil {
+		return errors.New("tpu: nil UDP endpoint")
+	}
+	if endpoint.Protocol != ProtocolUDP {
+		return fmt.Errorf("tpu: UDPTransport cannot send to a %s endpoint", endpoint.Protocol)
+	}
+	connection, err := net.DialUDP("udp", nil, endpoint.Addr)
+	if err != nil {
+		return err
+	}
+	defer connection.Close()
+	_, err = connection.Write(transaction)
+	return err
+}
+
+func (udpTransport *UDPTransport) Close() error {
+	return nil
+}
+
+// DeriveQUICPort returns the QUIC TPU port for a validator that advertises
+// only a UDP TPU socket, following the QUIC_PORT_OFFSET convention.
+func DeriveQUICPort(udpAddr *net.UDPAddr) *net.UDPAddr {
+	if udpAddr == nil {
+		return nil
+	}
+	return &net.UDPAddr{
+		IP:   udpAddr.IP,
+		Port: udpAddr.Port + int(QUIC_PORT_OFFSET),
+		Zone: udpAddr.Zone,
+	}
+}